@@ -0,0 +1,144 @@
+// Command envr runs one or more ENVR HTTP services in a single process.
+//
+// Usage:
+//
+//	envr serve all
+//	envr serve quantum-api fpga-status
+//	envr list
+//
+// Every service reads the same configuration (see package config) and
+// listens on its own address. A shutdown signal stops all of them; if one
+// service fails, the others are shut down too.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/shellworlds/ENVR/internal/fpgastatus"
+	"github.com/shellworlds/ENVR/internal/ptofhealth"
+	"github.com/shellworlds/ENVR/internal/quantumapi"
+	"github.com/shellworlds/ENVR/pkg/config"
+	"github.com/shellworlds/ENVR/pkg/server"
+)
+
+// service is one service that envr can run.
+type service struct {
+	defaults func() config.Service
+	handler  func() *http.ServeMux
+}
+
+// services lists the runnable services in the order "envr serve all" starts
+// them.
+var services = []service{
+	{quantumapi.Defaults, quantumapi.Handler},
+	{fpgastatus.Defaults, fpgastatus.Handler},
+	{ptofhealth.Defaults, ptofhealth.Handler},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "serve":
+		if err := serve(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+	case "list":
+		for _, s := range services {
+			d := s.defaults()
+			fmt.Printf("%-12s %s\n", d.Name, d.Addr)
+		}
+	case "help", "-h", "--help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "envr: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: envr serve all | envr serve SERVICE... | envr list")
+}
+
+// serve runs the named services until a shutdown signal or the first failure.
+func serve(names []string) error {
+	selected, err := selectServices(names)
+	if err != nil {
+		return err
+	}
+
+	// Load every config before starting anything so a bad setting does not
+	// leave the process half up.
+	servers := make([]*server.Server, len(selected))
+	for i, s := range selected {
+		cfg, err := config.Load(s.defaults())
+		if err != nil {
+			return err
+		}
+		servers[i] = server.New(cfg, s.handler())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(servers))
+	for i, srv := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if errs[i] = srv.Run(ctx); errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func selectServices(names []string) ([]service, error) {
+	if len(names) == 0 {
+		return nil, errors.New("envr serve: name at least one service, or all")
+	}
+	if len(names) == 1 && names[0] == "all" {
+		return services, nil
+	}
+
+	var out []service
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		s, ok := lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("envr serve: unknown service %q (see envr list)", name)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func lookup(name string) (service, bool) {
+	for _, s := range services {
+		if s.defaults().Name == name {
+			return s, true
+		}
+	}
+	return service{}, false
+}
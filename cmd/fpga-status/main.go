@@ -0,0 +1,21 @@
+// Command fpga-status serves the FPGA status service.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/shellworlds/ENVR/internal/fpgastatus"
+	"github.com/shellworlds/ENVR/pkg/config"
+	"github.com/shellworlds/ENVR/pkg/server"
+)
+
+func main() {
+	cfg, err := config.Load(fpgastatus.Defaults())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := server.New(cfg, fpgastatus.Handler()).Run(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/shellworlds/ENVR/pkg/config"
+)
+
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (s *healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return &grpc_health_v1.HealthCheckResponse{
+		Status: grpc_health_v1.HealthCheckResponse_SERVING,
+	}, nil
+}
+
+func (s *healthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	return nil
+}
+
+// warnIgnored logs the shared settings that only the HTTP services apply,
+// so a fleet-wide auth or CORS policy is not mistaken for covering gRPC.
+func warnIgnored(cfg config.Service) {
+	if cfg.Auth.Enabled() {
+		log.Printf("%s: auth settings are ignored; the gRPC server does not authenticate callers", cfg.Name)
+	}
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		log.Printf("%s: cors settings are ignored by the gRPC server", cfg.Name)
+	}
+	if cfg.RequestTimeout != config.DefaultRequestTimeout {
+		log.Printf("%s: request_timeout is ignored by the gRPC server", cfg.Name)
+	}
+	if cfg.DrainDelay > 0 {
+		log.Printf("%s: drain_delay is ignored by the gRPC server", cfg.Name)
+	}
+	if cfg.Tracing.Enabled() {
+		log.Printf("%s: tracing settings are ignored; the gRPC server is not traced", cfg.Name)
+	}
+}
+
+func main() {
+	cfg, err := config.Load(config.Service{Name: "grpc-health", Addr: ":50051"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	warnIgnored(cfg)
+
+	lis, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	s := grpc.NewServer()
+	healthServer := &healthServer{}
+	grpc_health_v1.RegisterHealthServer(s, healthServer)
+	log.Printf("gRPC Health server listening on %s", cfg.Addr)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		log.Println("gRPC Health server shutting down")
+		// GracefulStop waits for open RPCs without a deadline, so fall
+		// back to Stop once shutdown_timeout has passed.
+		timer := time.AfterFunc(cfg.ShutdownTimeout, s.Stop)
+		defer timer.Stop()
+		s.GracefulStop()
+	}()
+	if err := s.Serve(lis); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+// Command ptof-health serves the PT-OF health service.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/shellworlds/ENVR/internal/ptofhealth"
+	"github.com/shellworlds/ENVR/pkg/config"
+	"github.com/shellworlds/ENVR/pkg/server"
+)
+
+func main() {
+	cfg, err := config.Load(ptofhealth.Defaults())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := server.New(cfg, ptofhealth.Handler()).Run(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+}
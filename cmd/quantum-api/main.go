@@ -0,0 +1,30 @@
+// Command quantum-api serves the Quantum Go API.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/shellworlds/ENVR/internal/quantumapi"
+	"github.com/shellworlds/ENVR/pkg/config"
+	"github.com/shellworlds/ENVR/pkg/server"
+)
+
+func main() {
+	cfg, err := config.Load(quantumapi.Defaults())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Quantum Go API server starting on %s\n", cfg.Addr)
+	fmt.Printf("Endpoints:\n")
+	fmt.Printf("  GET /api/v1/quantum/health - Health check\n")
+	fmt.Printf("  GET /api/v1/quantum/bell - Create Bell state\n")
+	fmt.Printf("  GET /api/v1/quantum/simulate?qubits=N - Quantum simulation\n")
+	fmt.Printf("  GET /healthz, /readyz - Liveness and readiness probes\n")
+
+	if err := server.New(cfg, quantumapi.Handler()).Run(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+}
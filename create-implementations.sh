@@ -428,7 +428,8 @@ int main() {
 ENDCPP
 
 # 5. Go implementation
-cat > src/go/module_theorem.go << 'ENDGO'
+mkdir -p cmd/module-theorem
+cat > cmd/module-theorem/main.go << 'ENDGO'
 package main
 
 import (
@@ -569,6 +570,6 @@ echo "1. src/python/module_theorem.py"
 echo "2. theorem-explainer.sh"
 echo "3. src/java/ModuleTheorem.java"
 echo "4. src/cpp/module_theorem.cpp"
-echo "5. src/go/module_theorem.go"
+echo "5. cmd/module-theorem/main.go"
 echo ""
 echo "=== Ready for Step 4 (11 more files) ==="
@@ -0,0 +1,3 @@
+module github.com/shellworlds/ENVR/envr-platform
+
+go 1.23
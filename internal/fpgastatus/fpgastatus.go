@@ -0,0 +1,39 @@
+// Package fpgastatus implements the FPGA status service.
+package fpgastatus
+
+import (
+    "encoding/json"
+    "net/http"
+
+    "github.com/shellworlds/ENVR/pkg/config"
+    "github.com/shellworlds/ENVR/pkg/httpx"
+    "github.com/shellworlds/ENVR/pkg/problem"
+)
+
+type FpgaStatus struct {
+    Temperature float64 `json:"temp_c"`
+    DmaActive   bool    `json:"dma_active"`
+}
+
+func statusHandler(w http.ResponseWriter, r *http.Request) {
+    status := FpgaStatus{Temperature: 45.2, DmaActive: true}
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(status)
+}
+
+// Name is the service name used for configuration and metrics.
+const Name = "fpga-status"
+
+// Defaults returns the built-in settings for the service.
+func Defaults() config.Service {
+    return config.Service{Name: Name, Addr: ":8082"}
+}
+
+// Handler returns the service's routes.
+func Handler() *http.ServeMux {
+    mux := http.NewServeMux()
+    httpx.HandleFunc(mux, http.MethodGet, "/v1/fpga/status", statusHandler)
+    httpx.HandleFunc(mux, http.MethodGet, "/fpga/status", statusHandler)
+    mux.HandleFunc("/", problem.NotFound)
+    return mux
+}
@@ -0,0 +1,39 @@
+// Package ptofhealth implements the PT-OF health service.
+package ptofhealth
+
+import (
+    "fmt"
+    "net/http"
+
+    "github.com/shellworlds/ENVR/pkg/config"
+    "github.com/shellworlds/ENVR/pkg/httpx"
+    "github.com/shellworlds/ENVR/pkg/problem"
+)
+
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+    w.WriteHeader(http.StatusOK)
+    fmt.Fprintf(w, `{"status":"healthy","service":"ptof-1.6"}`)
+}
+
+// Name is the service name used for configuration and metrics.
+const Name = "ptof-health"
+
+// Defaults returns the built-in settings for the service.
+func Defaults() config.Service {
+    return config.Service{
+        Name: Name,
+        Addr: ":8081",
+        Auth: config.Auth{Policy: map[string][]string{
+            "/": {config.PolicyPublic},
+        }},
+    }
+}
+
+// Handler returns the service's routes.
+func Handler() *http.ServeMux {
+    mux := http.NewServeMux()
+    httpx.HandleFunc(mux, http.MethodGet, "/v1/health", healthHandler)
+    httpx.HandleFunc(mux, http.MethodGet, "/health", healthHandler)
+    mux.HandleFunc("/", problem.NotFound)
+    return mux
+}
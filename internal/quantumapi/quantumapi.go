@@ -3,12 +3,12 @@ Quantum JV Platform - Go Quantum API
 REST API server for quantum operations in Go
 */
 
-package main
+// Package quantumapi implements the Quantum Go API service.
+package quantumapi
 
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"math"
 	"math/cmplx"
 	"math/rand"
@@ -16,6 +16,10 @@ import (
 	"strconv"
 	"sync"
 	"time"
+
+	"github.com/shellworlds/ENVR/pkg/config"
+	"github.com/shellworlds/ENVR/pkg/httpx"
+	"github.com/shellworlds/ENVR/pkg/problem"
 )
 
 // QuantumState represents a quantum state vector
@@ -186,10 +190,15 @@ func bellStateHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func quantumSimHandler(w http.ResponseWriter, r *http.Request) {
-	qubitsStr := r.URL.Query().Get("qubits")
-	qubits, err := strconv.Atoi(qubitsStr)
-	if err != nil || qubits < 1 || qubits > 5 {
-		qubits = 2
+	qubits := 2
+	if qubitsStr := r.URL.Query().Get("qubits"); qubitsStr != "" {
+		n, err := strconv.Atoi(qubitsStr)
+		if err != nil || n < 1 || n > 5 {
+			problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidParameter,
+				fmt.Sprintf("qubits must be an integer from 1 to 5, got %q", qubitsStr))
+			return
+		}
+		qubits = n
 	}
 	
 	qs := NewQuantumState(qubits)
@@ -214,23 +223,41 @@ func quantumSimHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func main() {
+// Name is the service name used for configuration and metrics.
+const Name = "quantum-api"
+
+// Defaults returns the built-in settings for the service.
+func Defaults() config.Service {
+	return config.Service{
+		Name: Name,
+		Addr: ":8080",
+		Auth: config.Auth{Policy: map[string][]string{
+			"/":                      {config.PolicyPublic},
+			"/api/":                  {config.PolicyAuthenticated},
+			"/api/v1/quantum/health": {config.PolicyPublic},
+		}},
+	}
+}
+
+// Handler returns the service's routes.
+func Handler() *http.ServeMux {
 	// Register HTTP handlers
-	http.HandleFunc("/api/quantum/health", healthHandler)
-	http.HandleFunc("/api/quantum/bell", bellStateHandler)
-	http.HandleFunc("/api/quantum/simulate", quantumSimHandler)
+	mux := http.NewServeMux()
+	httpx.HandleFunc(mux, http.MethodGet, "/api/v1/quantum/health", healthHandler)
+	httpx.HandleFunc(mux, http.MethodGet, "/api/v1/quantum/bell", bellStateHandler)
+	httpx.HandleFunc(mux, http.MethodGet, "/api/v1/quantum/simulate", quantumSimHandler)
+	
+	// Unversioned paths kept for existing clients
+	httpx.HandleFunc(mux, http.MethodGet, "/api/quantum/health", healthHandler)
+	httpx.HandleFunc(mux, http.MethodGet, "/api/quantum/bell", bellStateHandler)
+	httpx.HandleFunc(mux, http.MethodGet, "/api/quantum/simulate", quantumSimHandler)
+	
+	// Unknown API paths, versioned or not
+	mux.HandleFunc("/api/", problem.NotFound)
 	
 	// Serve static files
 	fs := http.FileServer(http.Dir("./static"))
-	http.Handle("/", fs)
-	
-	// Start server
-	port := ":8080"
-	fmt.Printf("Quantum Go API server starting on port %s\n", port)
-	fmt.Printf("Endpoints:\n")
-	fmt.Printf("  GET /api/quantum/health - Health check\n")
-	fmt.Printf("  GET /api/quantum/bell - Create Bell state\n")
-	fmt.Printf("  GET /api/quantum/simulate?qubits=N - Quantum simulation\n")
+	mux.Handle("/", fs)
 	
-	log.Fatal(http.ListenAndServe(port, nil))
+	return mux
 }
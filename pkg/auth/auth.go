@@ -0,0 +1,144 @@
+// Package auth authenticates requests to the ENVR HTTP services with HS256
+// JWT bearer tokens or static API keys, and authorizes them against a
+// per-route role policy.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shellworlds/ENVR/pkg/config"
+	"github.com/shellworlds/ENVR/pkg/httpx"
+	"github.com/shellworlds/ENVR/pkg/problem"
+)
+
+// APIKeyHeader carries a static API key.
+const APIKeyHeader = "X-API-Key"
+
+// Principal is the authenticated caller.
+type Principal struct {
+	Subject string
+	Roles   []string
+}
+
+// HasRole reports whether p holds role.
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+type ctxKey struct{}
+
+// FromContext returns the caller stored by the middleware, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(ctxKey{}).(Principal)
+	return p, ok
+}
+
+// rule is one policy entry.
+type rule struct {
+	prefix string
+	roles  []string
+}
+
+// Authenticator checks credentials and route policy.
+type Authenticator struct {
+	cfg   config.Auth
+	rules []rule // longest prefix first
+	now   func() time.Time
+}
+
+// New returns an Authenticator for cfg, which config.Load has validated.
+func New(cfg config.Auth) *Authenticator {
+	a := &Authenticator{cfg: cfg, now: time.Now}
+	for prefix, roles := range cfg.Policy {
+		a.rules = append(a.rules, rule{prefix: httpx.Unversioned(prefix), roles: roles})
+	}
+	sort.Slice(a.rules, func(i, j int) bool {
+		return len(a.rules[i].prefix) > len(a.rules[j].prefix)
+	})
+	return a
+}
+
+// Middleware rejects requests that the policy does not admit. Policy
+// prefixes and request paths are compared without their /v1 segment, so a
+// rule covers a versioned route and its unversioned alias alike. When no
+// credentials are configured it passes every request through.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	if !a.cfg.Enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		roles := a.rolesFor(httpx.Unversioned(r.URL.Path))
+		if slices.Contains(roles, config.PolicyPublic) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		p, err := a.authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="envr"`)
+			problem.Error(w, r, http.StatusUnauthorized, problem.CodeUnauthorized, err.Error())
+			return
+		}
+		if !allowed(p, roles) {
+			problem.Error(w, r, http.StatusForbidden, problem.CodeForbidden,
+				"requires one of roles: "+strings.Join(roles, ", "))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, p)))
+	})
+}
+
+// rolesFor returns the policy entry with the longest prefix matching path.
+func (a *Authenticator) rolesFor(path string) []string {
+	for _, r := range a.rules {
+		if strings.HasPrefix(path, r.prefix) {
+			return r.roles
+		}
+	}
+	return []string{config.PolicyAuthenticated}
+}
+
+func allowed(p Principal, roles []string) bool {
+	for _, role := range roles {
+		if role == config.PolicyAuthenticated || p.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate resolves the caller from an API key or a bearer token.
+func (a *Authenticator) authenticate(r *http.Request) (Principal, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		for _, k := range a.cfg.APIKeys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(k.Key)) == 1 {
+				return Principal{Subject: k.Subject, Roles: k.Roles}, nil
+			}
+		}
+		return Principal{}, errInvalidAPIKey
+	}
+
+	// The scheme name is case-insensitive (RFC 7235).
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	token = strings.TrimSpace(token)
+	if !strings.EqualFold(scheme, "Bearer") || token == "" || a.cfg.JWTSecret == "" {
+		return Principal{}, errNoCredentials
+	}
+	c, err := verifyJWT(token, []byte(a.cfg.JWTSecret), a.now())
+	if err != nil {
+		return Principal{}, err
+	}
+	if a.cfg.JWTIssuer != "" && c.Issuer != a.cfg.JWTIssuer {
+		return Principal{}, errInvalidToken
+	}
+	if a.cfg.JWTAudience != "" && !slices.Contains(c.Audience, a.cfg.JWTAudience) {
+		return Principal{}, errInvalidToken
+	}
+	return Principal{Subject: c.Subject, Roles: c.Roles}, nil
+}
@@ -0,0 +1,305 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shellworlds/ENVR/pkg/config"
+	"github.com/shellworlds/ENVR/pkg/httpx"
+)
+
+const secret = "0123456789abcdef0123456789abcdef"
+
+var now = time.Unix(1_700_000_000, 0)
+
+// sign returns a compact JWT for header and payload, signed with key.
+func sign(t *testing.T, header, payload map[string]any, key string) string {
+	t.Helper()
+	enc := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	unsigned := enc(header) + "." + enc(payload)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func hs256() map[string]any { return map[string]any{"alg": "HS256", "typ": "JWT"} }
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":   "alice",
+		"iss":   "envr",
+		"roles": []string{"reader"},
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+}
+
+// with returns a copy of validClaims with key set to v, or removed if v is
+// nil.
+func with(key string, v any) map[string]any {
+	c := validClaims()
+	if v == nil {
+		delete(c, key)
+	} else {
+		c[key] = v
+	}
+	return c
+}
+
+func TestVerifyJWT(t *testing.T) {
+	unsigned := func(token string) string {
+		return token[:strings.LastIndex(token, ".")+1]
+	}
+	tamper := func(token string) string {
+		parts := strings.Split(token, ".")
+		payload, _ := json.Marshal(with("roles", []string{"admin"}))
+		parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+		return strings.Join(parts, ".")
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"valid", sign(t, hs256(), validClaims(), secret), nil},
+		{"alg none", sign(t, map[string]any{"alg": "none"}, validClaims(), secret), errInvalidToken},
+		{"alg none unsigned", unsigned(sign(t, map[string]any{"alg": "none"}, validClaims(), secret)), errInvalidToken},
+		{"alg HS512", sign(t, map[string]any{"alg": "HS512"}, validClaims(), secret), errInvalidToken},
+		{"alg RS256", sign(t, map[string]any{"alg": "RS256"}, validClaims(), secret), errInvalidToken},
+		{"wrong secret", sign(t, hs256(), validClaims(), strings.Repeat("x", 32)), errInvalidToken},
+		{"tampered payload", tamper(sign(t, hs256(), validClaims(), secret)), errInvalidToken},
+		{"malformed", "not.a-jwt", errInvalidToken},
+		{"missing exp", sign(t, hs256(), with("exp", nil), secret), errExpiredToken},
+		{"expired", sign(t, hs256(), with("exp", now.Add(-time.Second).Unix()), secret), errExpiredToken},
+		{"expires now", sign(t, hs256(), with("exp", now.Unix()), secret), errExpiredToken},
+		{"future nbf", sign(t, hs256(), with("nbf", now.Add(time.Minute).Unix()), secret), errExpiredToken},
+		{"past nbf", sign(t, hs256(), with("nbf", now.Add(-time.Minute).Unix()), secret), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := verifyJWT(tt.token, []byte(secret), now)
+			if err != tt.err {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err == nil && c.Subject != "alice" {
+				t.Errorf("subject = %q, want alice", c.Subject)
+			}
+		})
+	}
+}
+
+func newAuthenticator(cfg config.Auth) *Authenticator {
+	a := New(cfg)
+	a.now = func() time.Time { return now }
+	return a
+}
+
+// serve runs a request through a's middleware and returns the status code
+// and the subject the handler saw.
+func serve(a *Authenticator, path string, header http.Header) (int, string) {
+	var subject string
+	h := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := FromContext(r.Context()); ok {
+			subject = p.Subject
+		}
+	}))
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, vs := range header {
+		for _, v := range vs {
+			r.Header.Add(k, v)
+		}
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code, subject
+}
+
+func bearer(token string) http.Header {
+	return http.Header{"Authorization": {"Bearer " + token}}
+}
+
+func TestIssuer(t *testing.T) {
+	a := newAuthenticator(config.Auth{JWTSecret: secret, JWTIssuer: "envr"})
+	tests := []struct {
+		name string
+		iss  any
+		want int
+	}{
+		{"match", "envr", http.StatusOK},
+		{"mismatch", "other", http.StatusUnauthorized},
+		{"missing", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := sign(t, hs256(), with("iss", tt.iss), secret)
+			if code, _ := serve(a, "/api/x", bearer(token)); code != tt.want {
+				t.Errorf("status = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAudience(t *testing.T) {
+	a := newAuthenticator(config.Auth{JWTSecret: secret, JWTAudience: "quantum-api"})
+	tests := []struct {
+		name string
+		aud  any
+		want int
+	}{
+		{"string", "quantum-api", http.StatusOK},
+		{"array", []string{"fpga-status", "quantum-api"}, http.StatusOK},
+		{"other service", "fpga-status", http.StatusUnauthorized},
+		{"missing", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := sign(t, hs256(), with("aud", tt.aud), secret)
+			if code, _ := serve(a, "/api/x", bearer(token)); code != tt.want {
+				t.Errorf("status = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestBearerScheme(t *testing.T) {
+	a := newAuthenticator(config.Auth{JWTSecret: secret})
+	token := sign(t, hs256(), validClaims(), secret)
+	tests := []struct {
+		header string
+		want   int
+	}{
+		{"Bearer " + token, http.StatusOK},
+		{"bearer " + token, http.StatusOK},
+		{"BEARER " + token, http.StatusOK},
+		{"Basic " + token, http.StatusUnauthorized},
+		{"Bearer", http.StatusUnauthorized},
+		{token, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(strings.SplitN(tt.header, ".", 2)[0], func(t *testing.T) {
+			h := http.Header{"Authorization": {tt.header}}
+			if code, _ := serve(a, "/api/x", h); code != tt.want {
+				t.Errorf("status = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRolesFor(t *testing.T) {
+	a := newAuthenticator(config.Auth{JWTSecret: secret, Policy: map[string][]string{
+		"/":                         {config.PolicyPublic},
+		"/api/":                     {config.PolicyAuthenticated},
+		"/api/v1/quantum/":          {"reader"},
+		"/api/v1/quantum/simulate":  {"admin"},
+		"/api/quantum/health":       {config.PolicyPublic},
+		"/api/v1/quantum/simulated": {"ops"},
+	}})
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/index.html", []string{config.PolicyPublic}},
+		{"/api/other", []string{config.PolicyAuthenticated}},
+		{"/api/v1/quantum/bell", []string{"reader"}},
+		{"/api/quantum/bell", []string{"reader"}},
+		{"/api/v1/quantum/simulate", []string{"admin"}},
+		{"/api/quantum/simulate", []string{"admin"}},
+		{"/api/quantum/simulated", []string{"ops"}},
+		{"/api/v1/quantum/health", []string{config.PolicyPublic}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := a.rolesFor(httpx.Unversioned(tt.path)); !slices.Equal(got, tt.want) {
+				t.Errorf("rolesFor(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	none := newAuthenticator(config.Auth{JWTSecret: secret})
+	if got := none.rolesFor("/anything"); !slices.Equal(got, []string{config.PolicyAuthenticated}) {
+		t.Errorf("rolesFor with no policy = %v, want [authenticated]", got)
+	}
+}
+
+func TestPolicyKeywords(t *testing.T) {
+	a := newAuthenticator(config.Auth{JWTSecret: secret, Policy: map[string][]string{
+		"/public/": {config.PolicyPublic},
+		"/authn/":  {config.PolicyAuthenticated},
+		"/admin/":  {"admin"},
+	}})
+	reader := bearer(sign(t, hs256(), validClaims(), secret))
+	admin := bearer(sign(t, hs256(), with("roles", []string{"admin"}), secret))
+
+	tests := []struct {
+		name   string
+		path   string
+		header http.Header
+		want   int
+	}{
+		{"public without credentials", "/public/x", nil, http.StatusOK},
+		{"public with bad credentials", "/public/x", bearer("bad"), http.StatusOK},
+		{"authenticated without credentials", "/authn/x", nil, http.StatusUnauthorized},
+		{"authenticated with any role", "/authn/x", reader, http.StatusOK},
+		{"role without credentials", "/admin/x", nil, http.StatusUnauthorized},
+		{"role missing", "/admin/x", reader, http.StatusForbidden},
+		{"role held", "/admin/x", admin, http.StatusOK},
+		{"unlisted path needs credentials", "/other", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _ := serve(a, tt.path, tt.header); code != tt.want {
+				t.Errorf("status = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialPrecedence(t *testing.T) {
+	a := newAuthenticator(config.Auth{
+		JWTSecret: secret,
+		APIKeys:   []config.APIKey{{Key: "k1", Subject: "ci", Roles: []string{"reader"}}},
+	})
+	token := sign(t, hs256(), validClaims(), secret)
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		code    int
+		subject string
+	}{
+		{"api key", http.Header{APIKeyHeader: {"k1"}}, http.StatusOK, "ci"},
+		{"bearer", bearer(token), http.StatusOK, "alice"},
+		{"api key wins over bearer", http.Header{APIKeyHeader: {"k1"}, "Authorization": {"Bearer " + token}}, http.StatusOK, "ci"},
+		{"bad api key does not fall back to bearer", http.Header{APIKeyHeader: {"nope"}, "Authorization": {"Bearer " + token}}, http.StatusUnauthorized, ""},
+		{"no credentials", nil, http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, subject := serve(a, "/api/x", tt.header)
+			if code != tt.code || subject != tt.subject {
+				t.Errorf("got %d %q, want %d %q", code, subject, tt.code, tt.subject)
+			}
+		})
+	}
+}
+
+func TestDisabledPassesThrough(t *testing.T) {
+	a := newAuthenticator(config.Auth{Policy: map[string][]string{"/": {"admin"}}})
+	if code, _ := serve(a, "/api/x", nil); code != http.StatusOK {
+		t.Errorf("status = %d, want 200 with authentication off", code)
+	}
+}
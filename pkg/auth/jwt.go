@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	errNoCredentials = errors.New("missing credentials")
+	errInvalidAPIKey = errors.New("invalid API key")
+	errInvalidToken  = errors.New("invalid bearer token")
+	errExpiredToken  = errors.New("bearer token expired or not yet valid")
+)
+
+// claims are the JWT claims the services read.
+type claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	Roles     []string `json:"roles"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+}
+
+// audience is the aud claim, which RFC 7519 allows as a single string or
+// an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(a))
+}
+
+// verifyJWT checks an HS256 compact JWT against secret and returns its
+// claims. Tokens without an exp claim are rejected.
+func verifyJWT(token string, secret []byte, now time.Time) (claims, error) {
+	var c claims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return c, errInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return c, errInvalidToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return c, errInvalidToken
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return c, errInvalidToken
+	}
+
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return c, errInvalidToken
+	}
+	if c.ExpiresAt == 0 || now.Unix() >= c.ExpiresAt || now.Unix() < c.NotBefore {
+		return c, errExpiredToken
+	}
+	return c, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shellworlds/ENVR/pkg/httpx"
+)
+
+// Policy keywords accepted in Auth.Policy alongside role names.
+const (
+	// PolicyPublic lets requests through without credentials.
+	PolicyPublic = "public"
+	// PolicyAuthenticated admits any caller with valid credentials.
+	PolicyAuthenticated = "authenticated"
+)
+
+// minJWTSecret is the shortest HS256 secret accepted.
+const minJWTSecret = 32
+
+// Auth configures authentication for a service. Authentication is off
+// unless JWTSecret or at least one API key is set.
+type Auth struct {
+	// JWTSecret verifies HS256 bearer tokens. A secret set under
+	// "defaults" is shared by every service, so a token for one service
+	// is accepted by all of them unless each sets its own JWTAudience or
+	// its own secret.
+	JWTSecret string `yaml:"jwt_secret"`
+	// JWTIssuer, if set, must match the token's iss claim.
+	JWTIssuer string `yaml:"jwt_issuer"`
+	// JWTAudience, if set, must be one of the token's aud values.
+	JWTAudience string `yaml:"jwt_audience"`
+	// APIKeys are static keys accepted in the X-API-Key header.
+	APIKeys []APIKey `yaml:"api_keys"`
+	// Policy maps a path prefix to the roles allowed to call it, or to
+	// PolicyPublic or PolicyAuthenticated. The longest matching prefix
+	// wins; paths that match no prefix require PolicyAuthenticated. A
+	// prefix applies with and without its /v1 segment, so
+	// "/api/v1/quantum/simulate" also covers "/api/quantum/simulate".
+	Policy map[string][]string `yaml:"policy"`
+}
+
+// APIKey is a static credential and the identity it grants.
+type APIKey struct {
+	Key     string   `yaml:"key"`
+	Subject string   `yaml:"subject"`
+	Roles   []string `yaml:"roles"`
+}
+
+// Enabled reports whether any credentials are configured.
+func (a Auth) Enabled() bool {
+	return a.JWTSecret != "" || len(a.APIKeys) > 0
+}
+
+func (a *Auth) merge(o Auth) {
+	if o.JWTSecret != "" {
+		a.JWTSecret = o.JWTSecret
+	}
+	if o.JWTIssuer != "" {
+		a.JWTIssuer = o.JWTIssuer
+	}
+	if o.JWTAudience != "" {
+		a.JWTAudience = o.JWTAudience
+	}
+	if len(o.APIKeys) > 0 {
+		a.APIKeys = o.APIKeys
+	}
+	if len(o.Policy) > 0 {
+		policy := make(map[string][]string, len(a.Policy)+len(o.Policy))
+		for prefix, roles := range a.Policy {
+			policy[prefix] = roles
+		}
+		// An override replaces the entry for the same route under either
+		// spelling, versioned or not.
+		for prefix := range o.Policy {
+			for old := range policy {
+				if httpx.Unversioned(old) == httpx.Unversioned(prefix) {
+					delete(policy, old)
+				}
+			}
+		}
+		for prefix, roles := range o.Policy {
+			policy[prefix] = roles
+		}
+		a.Policy = policy
+	}
+}
+
+func (s *Service) applyAuthEnv() error {
+	if v, ok := s.lookupEnv("JWT_SECRET"); ok {
+		s.Auth.JWTSecret = v
+	}
+	if v, ok := s.lookupEnv("JWT_ISSUER"); ok {
+		s.Auth.JWTIssuer = v
+	}
+	if v, ok := s.lookupEnv("JWT_AUDIENCE"); ok {
+		s.Auth.JWTAudience = v
+	}
+	if v, ok := s.lookupEnv("API_KEYS"); ok {
+		keys, err := parseAPIKeys(v)
+		if err != nil {
+			return fmt.Errorf("config: %s: %w", s.EnvKey("API_KEYS"), err)
+		}
+		s.Auth.APIKeys = keys
+	}
+	return nil
+}
+
+// parseAPIKeys reads the ENVR_<SERVICE>_API_KEYS format:
+// "subject:key:role|role,subject:key:role".
+func parseAPIKeys(v string) ([]APIKey, error) {
+	var keys []APIKey
+	for _, entry := range strings.Split(v, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("api key entry must be subject:key:roles, got %d fields", len(parts))
+		}
+		keys = append(keys, APIKey{
+			Subject: parts[0],
+			Key:     parts[1],
+			Roles:   strings.Split(parts[2], "|"),
+		})
+	}
+	return keys, nil
+}
+
+func (a Auth) validate(name string) error {
+	if a.JWTSecret != "" && len(a.JWTSecret) < minJWTSecret {
+		return fmt.Errorf("config: %s: jwt_secret must be at least %d bytes", name, minJWTSecret)
+	}
+	for i, k := range a.APIKeys {
+		if k.Key == "" || k.Subject == "" {
+			return fmt.Errorf("config: %s: api_keys[%d] needs a key and a subject", name, i)
+		}
+	}
+	routes := make(map[string]string, len(a.Policy))
+	for prefix, roles := range a.Policy {
+		if !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("config: %s: policy prefix %q must start with /", name, prefix)
+		}
+		if len(roles) == 0 {
+			return fmt.Errorf("config: %s: policy for %q lists no roles", name, prefix)
+		}
+		route := httpx.Unversioned(prefix)
+		if other, ok := routes[route]; ok && !slices.Equal(roles, a.Policy[other]) {
+			return fmt.Errorf("config: %s: policy entries %q and %q cover the same route with different roles", name, other, prefix)
+		}
+		routes[route] = prefix
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Package config loads per-service settings for the ENVR Go services.
+//
+// Values are resolved in this order, later sources winning:
+//
+//  1. the defaults passed to Load by the service,
+//  2. the "defaults" section of the YAML file named by ENVR_CONFIG,
+//  3. the service's entry under "services" in that file,
+//  4. environment variables named ENVR_<SERVICE>_<KEY>, for example
+//     ENVR_QUANTUM_API_ADDR.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable that points at the YAML file.
+const FileEnv = "ENVR_CONFIG"
+
+// ServiceNames lists every service that reads the shared config file. An
+// entry under "services" with any other name is rejected, so a misspelled
+// name cannot silently drop that service's settings.
+var ServiceNames = []string{"quantum-api", "fpga-status", "ptof-health", "grpc-health"}
+
+// Service holds the settings for one service.
+type Service struct {
+	Name string `yaml:"-"`
+	Addr string `yaml:"addr"`
+
+	// ShutdownTimeout bounds how long in-flight requests may take to
+	// finish after a shutdown signal.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// DrainDelay is how long /readyz reports not-ready before the
+	// listener closes, so load balancers can stop routing first.
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	// RequestTimeout is the deadline placed on each request's context.
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+
+	Auth    Auth    `yaml:"auth"`
+	CORS    CORS    `yaml:"cors"`
+	Tracing Tracing `yaml:"tracing"`
+}
+
+// Defaults used for fields a service leaves unset.
+const (
+	DefaultShutdownTimeout = 10 * time.Second
+	DefaultRequestTimeout  = 30 * time.Second
+)
+
+// file is the layout of the YAML config file.
+type file struct {
+	Defaults Service            `yaml:"defaults"`
+	Services map[string]Service `yaml:"services"`
+}
+
+// Load resolves the settings for the service named by defaults.Name.
+func Load(defaults Service) (Service, error) {
+	cfg := defaults
+	if cfg.ShutdownTimeout == 0 {
+		cfg.ShutdownTimeout = DefaultShutdownTimeout
+	}
+	if cfg.RequestTimeout == 0 {
+		cfg.RequestTimeout = DefaultRequestTimeout
+	}
+	if cfg.Name == "" {
+		return cfg, errors.New("config: service name is required")
+	}
+	if !slices.Contains(ServiceNames, cfg.Name) {
+		return cfg, fmt.Errorf("config: unknown service %q", cfg.Name)
+	}
+
+	if path := os.Getenv(FileEnv); path != "" {
+		f, err := readFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.merge(f.Defaults)
+		cfg.merge(f.Services[cfg.Name])
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return cfg, err
+	}
+	if err := cfg.validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func readFile(path string) (file, error) {
+	var f file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return f, fmt.Errorf("config: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return f, fmt.Errorf("config: %s: %w", path, err)
+	}
+	for name := range f.Services {
+		if !slices.Contains(ServiceNames, name) {
+			return f, fmt.Errorf("config: %s: unknown service %q (known: %s)",
+				path, name, strings.Join(ServiceNames, ", "))
+		}
+	}
+	return f, nil
+}
+
+// merge copies the fields that are set in o onto s.
+func (s *Service) merge(o Service) {
+	if o.Addr != "" {
+		s.Addr = o.Addr
+	}
+	if o.ShutdownTimeout != 0 {
+		s.ShutdownTimeout = o.ShutdownTimeout
+	}
+	if o.DrainDelay != 0 {
+		s.DrainDelay = o.DrainDelay
+	}
+	if o.RequestTimeout != 0 {
+		s.RequestTimeout = o.RequestTimeout
+	}
+	s.Auth.merge(o.Auth)
+	s.CORS.merge(o.CORS)
+	s.Tracing.merge(o.Tracing)
+}
+
+func (s *Service) applyEnv() error {
+	if v, ok := s.lookupEnv("ADDR"); ok {
+		s.Addr = v
+	}
+	if err := s.durationEnv("SHUTDOWN_TIMEOUT", &s.ShutdownTimeout); err != nil {
+		return err
+	}
+	if err := s.durationEnv("DRAIN_DELAY", &s.DrainDelay); err != nil {
+		return err
+	}
+	if err := s.durationEnv("REQUEST_TIMEOUT", &s.RequestTimeout); err != nil {
+		return err
+	}
+	if err := s.applyAuthEnv(); err != nil {
+		return err
+	}
+	s.applyTracingEnv()
+	return s.applyCORSEnv()
+}
+
+func (s Service) durationEnv(key string, dst *time.Duration) error {
+	v, ok := s.lookupEnv(key)
+	if !ok {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("config: %s: %w", s.EnvKey(key), err)
+	}
+	*dst = d
+	return nil
+}
+
+// EnvKey returns the environment variable that overrides key for s.
+func (s Service) EnvKey(key string) string {
+	name := strings.ToUpper(strings.ReplaceAll(s.Name, "-", "_"))
+	return "ENVR_" + name + "_" + key
+}
+
+func (s Service) lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(s.EnvKey(key))
+}
+
+func (s Service) validate() error {
+	_, port, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return fmt.Errorf("config: %s: invalid addr %q: %w", s.Name, s.Addr, err)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("config: %s: invalid port in addr %q", s.Name, s.Addr)
+	}
+	if s.ShutdownTimeout <= 0 || s.RequestTimeout <= 0 || s.DrainDelay < 0 {
+		return fmt.Errorf("config: %s: shutdown_timeout and request_timeout must be positive, drain_delay non-negative", s.Name)
+	}
+	if err := s.Auth.validate(s.Name); err != nil {
+		return err
+	}
+	if err := s.CORS.validate(s.Name); err != nil {
+		return err
+	}
+	return s.Tracing.validate(s.Name)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CORS configures cross-origin access for a service. CORS headers are only
+// sent once AllowedOrigins is non-empty.
+type CORS struct {
+	// AllowedOrigins lists exact origins, e.g. "https://app.example.com",
+	// or "*" for any origin.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	// AllowedMethods defaults to GET, HEAD and POST.
+	AllowedMethods []string `yaml:"allowed_methods"`
+	// AllowedHeaders defaults to the headers the services read.
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+func (c *CORS) merge(o CORS) {
+	if len(o.AllowedOrigins) > 0 {
+		c.AllowedOrigins = o.AllowedOrigins
+	}
+	if len(o.AllowedMethods) > 0 {
+		c.AllowedMethods = o.AllowedMethods
+	}
+	if len(o.AllowedHeaders) > 0 {
+		c.AllowedHeaders = o.AllowedHeaders
+	}
+	if o.AllowCredentials {
+		c.AllowCredentials = true
+	}
+	if o.MaxAge != 0 {
+		c.MaxAge = o.MaxAge
+	}
+}
+
+func (s *Service) applyCORSEnv() error {
+	if v, ok := s.lookupEnv("CORS_ORIGINS"); ok {
+		s.CORS.AllowedOrigins = splitList(v)
+	}
+	if v, ok := s.lookupEnv("CORS_METHODS"); ok {
+		s.CORS.AllowedMethods = splitList(v)
+	}
+	if v, ok := s.lookupEnv("CORS_HEADERS"); ok {
+		s.CORS.AllowedHeaders = splitList(v)
+	}
+	return s.durationEnv("CORS_MAX_AGE", &s.CORS.MaxAge)
+}
+
+// splitList splits a comma-separated value, dropping empty items.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (c CORS) validate(name string) error {
+	if c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		return fmt.Errorf("config: %s: cors allow_credentials cannot be combined with origin *", name)
+	}
+	for _, o := range c.AllowedOrigins {
+		if o != "*" && !strings.HasPrefix(o, "http://") && !strings.HasPrefix(o, "https://") {
+			return fmt.Errorf("config: %s: cors origin %q must be * or start with http:// or https://", name, o)
+		}
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("config: %s: cors max_age must not be negative", name)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tracing configures OpenTelemetry tracing for a service. Spans are only
+// exported once OTLPEndpoint is set; trace context is propagated either way.
+type Tracing struct {
+	// OTLPEndpoint is the OTLP/HTTP collector URL, e.g.
+	// "http://localhost:4318". Spans are posted to its /v1/traces path.
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+}
+
+// Enabled reports whether spans are exported.
+func (t Tracing) Enabled() bool {
+	return t.OTLPEndpoint != ""
+}
+
+func (t *Tracing) merge(o Tracing) {
+	if o.OTLPEndpoint != "" {
+		t.OTLPEndpoint = o.OTLPEndpoint
+	}
+}
+
+func (s *Service) applyTracingEnv() {
+	if v, ok := s.lookupEnv("OTLP_ENDPOINT"); ok {
+		s.Tracing.OTLPEndpoint = v
+	}
+}
+
+func (t Tracing) validate(name string) error {
+	e := t.OTLPEndpoint
+	if e != "" && !strings.HasPrefix(e, "http://") && !strings.HasPrefix(e, "https://") {
+		return fmt.Errorf("config: %s: tracing otlp_endpoint %q must start with http:// or https://", name, e)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Package cors applies a service's cross-origin policy to HTTP requests.
+package cors
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shellworlds/ENVR/pkg/config"
+)
+
+var (
+	defaultMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	defaultHeaders = []string{"Authorization", "Content-Type", "X-API-Key", "X-Request-ID"}
+	exposedHeaders = "X-Request-ID"
+)
+
+// Policy is a compiled CORS configuration.
+type Policy struct {
+	cfg     config.CORS
+	any     bool
+	methods string
+	headers string
+}
+
+// New compiles cfg, which config.Load has validated.
+func New(cfg config.CORS) *Policy {
+	p := &Policy{cfg: cfg, any: slices.Contains(cfg.AllowedOrigins, "*")}
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultMethods
+	}
+	headers := cfg.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultHeaders
+	}
+	p.methods = strings.Join(methods, ", ")
+	p.headers = strings.Join(headers, ", ")
+	return p
+}
+
+// AllowOrigin reports whether origin may call the service. WebSocket
+// upgraders should use it as their origin check so both paths agree.
+func (p *Policy) AllowOrigin(origin string) bool {
+	return p.any || slices.Contains(p.cfg.AllowedOrigins, origin)
+}
+
+// Middleware adds CORS headers for allowed origins and answers preflight
+// requests itself, before authentication runs. With no allowed origins it
+// passes requests through unchanged.
+func (p *Policy) Middleware(next http.Handler) http.Handler {
+	if len(p.cfg.AllowedOrigins) == 0 {
+		return next
+	}
+	// Unless every origin gets "*", the response depends on Origin, and
+	// caches must key on it even for requests that sent none.
+	varies := !p.any || p.cfg.AllowCredentials
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		if varies {
+			h.Add("Vary", "Origin")
+		}
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if !p.AllowOrigin(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if p.any && !p.cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if p.cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			h.Set("Access-Control-Expose-Headers", exposedHeaders)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", p.methods)
+		h.Set("Access-Control-Allow-Headers", p.headers)
+		if p.cfg.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", strconv.Itoa(int(p.cfg.MaxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,45 @@
+package httpx
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/shellworlds/ENVR/pkg/problem"
+)
+
+// HandleFunc registers h on mux for method and path only. Requests to path
+// with any other method get a 405 method_not_allowed problem. A GET route
+// also serves HEAD, as ServeMux does.
+func HandleFunc(mux *http.ServeMux, method, path string, h http.HandlerFunc) {
+	allow := []string{method}
+	if method == http.MethodGet {
+		allow = append(allow, http.MethodHead)
+	}
+	mux.HandleFunc(method+" "+path, h)
+	mux.HandleFunc(path, problem.MethodNotAllowed(allow...))
+}
+
+// PatternPath returns the path part of a ServeMux pattern, dropping a
+// leading method such as "GET ".
+func PatternPath(pattern string) string {
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		return pattern[i:]
+	}
+	return pattern
+}
+
+// Unversioned returns path without its first "v1" segment. The services
+// serve every versioned route under an unversioned alias as well, so
+// "/api/v1/quantum/bell" and "/api/quantum/bell" both map to
+// "/api/quantum/bell".
+func Unversioned(path string) string {
+	i := strings.Index(path+"/", "/v1/")
+	if i < 0 {
+		return path
+	}
+	rest := path[i+len("/v1"):]
+	if rest == "" {
+		rest = "/"
+	}
+	return path[:i] + rest
+}
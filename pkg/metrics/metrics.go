@@ -0,0 +1,97 @@
+// Package metrics records Prometheus request metrics for the ENVR HTTP
+// services and serves them on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/shellworlds/ENVR/pkg/httpx"
+)
+
+// Path is where Handler is mounted.
+const Path = "/metrics"
+
+// unmatched labels requests that no route pattern matched, so stray paths
+// do not create new series.
+const unmatched = "unmatched"
+
+var (
+	requests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "envr_http_requests_total",
+		Help: "HTTP requests by service, route, method and status code.",
+	}, []string{"service", "route", "method", "code"})
+
+	duration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "envr_http_request_duration_seconds",
+		Help:    "HTTP request latency by service, route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"service", "route", "method"})
+
+	requestSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "envr_http_request_size_bytes",
+		Help:    "HTTP request body size by service and route.",
+		Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+	}, []string{"service", "route"})
+
+	responseSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "envr_http_response_size_bytes",
+		Help:    "HTTP response body size by service and route.",
+		Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+	}, []string{"service", "route"})
+
+	inFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "envr_http_requests_in_flight",
+		Help: "HTTP requests currently being served.",
+	}, []string{"service"})
+)
+
+// Handler serves the metrics of every service in the process.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware records metrics for requests to next. The route label is the
+// path of the ServeMux pattern that mux would pick for the request.
+func Middleware(service string, mux *http.ServeMux, next http.Handler) http.Handler {
+	gauge := inFlight.WithLabelValues(service)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := unmatched
+		if mux != nil {
+			if _, pattern := mux.Handler(r); pattern != "" {
+				route = httpx.PatternPath(pattern)
+			}
+		}
+		method := methodLabel(r.Method)
+
+		gauge.Inc()
+		defer gauge.Dec()
+		start := time.Now()
+		rec := httpx.NewRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		requests.WithLabelValues(service, route, method, strconv.Itoa(rec.Status)).Inc()
+		duration.WithLabelValues(service, route, method).Observe(time.Since(start).Seconds())
+		if r.ContentLength > 0 {
+			requestSize.WithLabelValues(service, route).Observe(float64(r.ContentLength))
+		} else {
+			requestSize.WithLabelValues(service, route).Observe(0)
+		}
+		responseSize.WithLabelValues(service, route).Observe(float64(rec.Bytes))
+	})
+}
+
+// methodLabel folds non-standard methods into one label value.
+func methodLabel(m string) string {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return m
+	}
+	return "OTHER"
+}
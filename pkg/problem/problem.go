@@ -0,0 +1,79 @@
+// Package problem writes RFC 7807 (application/problem+json) error responses
+// for the ENVR HTTP services.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/shellworlds/ENVR/pkg/requestid"
+)
+
+// ContentType is the media type of a problem response.
+const ContentType = "application/problem+json"
+
+// Error codes shared by the services. Clients should match on Code rather
+// than on Title or Detail.
+const (
+	CodeInvalidParameter = "invalid_parameter"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeUnavailable      = "unavailable"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+)
+
+// Problem is an RFC 7807 problem details object with an added error code.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	// RequestID matches the X-Request-ID response header.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// New returns a problem for status with the standard status text as title.
+func New(r *http.Request, status int, code, detail string) Problem {
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: requestid.FromContext(r.Context()),
+	}
+}
+
+// Write sends p as the response.
+func (p Problem) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// Error writes a problem response for status.
+func Error(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	New(r, status, code, detail).Write(w)
+}
+
+// NotFound is a handler that answers every request with a 404 problem.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	Error(w, r, http.StatusNotFound, CodeNotFound, "no such endpoint")
+}
+
+// MethodNotAllowed returns a handler that answers with a 405 problem and an
+// Allow header listing allow.
+func MethodNotAllowed(allow ...string) http.HandlerFunc {
+	methods := strings.Join(allow, ", ")
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", methods)
+		Error(w, r, http.StatusMethodNotAllowed, CodeMethodNotAllowed,
+			r.Method+" is not allowed; use "+methods)
+	}
+}
@@ -0,0 +1,176 @@
+// Package server runs an ENVR HTTP service: it serves the service's handler
+// next to /healthz and /readyz probes and shuts down gracefully on SIGINT or
+// SIGTERM.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/shellworlds/ENVR/pkg/auth"
+	"github.com/shellworlds/ENVR/pkg/config"
+	"github.com/shellworlds/ENVR/pkg/cors"
+	"github.com/shellworlds/ENVR/pkg/httpx"
+	"github.com/shellworlds/ENVR/pkg/metrics"
+	"github.com/shellworlds/ENVR/pkg/problem"
+	"github.com/shellworlds/ENVR/pkg/requestid"
+	"github.com/shellworlds/ENVR/pkg/tracing"
+)
+
+// Server is an HTTP service with liveness and readiness probes.
+type Server struct {
+	cfg    config.Service
+	srv    *http.Server
+	tracer *tracing.Provider
+	ready  atomic.Bool
+}
+
+// New returns a Server that serves h on cfg.Addr. The probe paths /healthz
+// and /readyz and the Prometheus endpoint /metrics take precedence over h
+// and are never authenticated. Requests to h get an X-Request-ID, an access
+// log line, request metrics, a trace span, the cfg.CORS and cfg.Auth
+// policies and a context deadline of cfg.RequestTimeout. CORS preflights are
+// answered before authentication. If h is a *http.ServeMux its patterns
+// label the metrics and name the spans.
+func New(cfg config.Service, h http.Handler) *Server {
+	s := &Server{cfg: cfg}
+	routes, _ := h.(*http.ServeMux)
+
+	if !cfg.Auth.Enabled() {
+		log.Printf("%s: no credentials configured, authentication is off", cfg.Name)
+	}
+	tracer, err := tracing.New(cfg.Name, cfg.Tracing)
+	if err != nil {
+		log.Printf("%s: tracing is off: %v", cfg.Name, err)
+		tracer, _ = tracing.New(cfg.Name, config.Tracing{})
+	} else if cfg.Tracing.Enabled() {
+		log.Printf("%s: exporting traces to %s", cfg.Name, cfg.Tracing.OTLPEndpoint)
+	}
+	s.tracer = tracer
+
+	h = auth.New(cfg.Auth).Middleware(h)
+	h = withTimeout(cfg.RequestTimeout, h)
+	h = cors.New(cfg.CORS).Middleware(h)
+	h = tracer.Middleware(routes, h)
+	h = metrics.Middleware(cfg.Name, routes, h)
+	h = logRequests(cfg.Name, h)
+	h = requestid.Middleware(h)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/readyz", s.readyz)
+	mux.Handle(metrics.Path, metrics.Handler())
+	mux.Handle("/", h)
+
+	s.srv = &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Run serves until ctx is cancelled or the process receives SIGINT or
+// SIGTERM. It then reports not-ready for cfg.DrainDelay, stops accepting
+// connections and waits up to cfg.ShutdownTimeout for in-flight requests.
+// Buffered spans are exported before Run returns.
+func (s *Server) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer s.flushTraces()
+
+	ln, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.cfg.Name, err)
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- s.srv.Serve(ln) }()
+	s.ready.Store(true)
+	log.Printf("%s listening on %s", s.cfg.Name, ln.Addr())
+
+	select {
+	case err := <-errc:
+		s.ready.Store(false)
+		return err
+	case <-ctx.Done():
+	}
+
+	s.ready.Store(false)
+	log.Printf("%s shutting down", s.cfg.Name)
+	if s.cfg.DrainDelay > 0 {
+		time.Sleep(s.cfg.DrainDelay)
+	}
+
+	sctx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+	if err := s.srv.Shutdown(sctx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	log.Printf("%s stopped", s.cfg.Name)
+	return nil
+}
+
+// flushTraces exports buffered spans, waiting up to cfg.ShutdownTimeout.
+func (s *Server) flushTraces() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+	if err := s.tracer.Shutdown(ctx); err != nil {
+		log.Printf("%s: exporting traces: %v", s.cfg.Name, err)
+	}
+}
+
+// withTimeout bounds each request's context by d.
+func withTimeout(d time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// logRequests writes one access log line per request, tagged with its
+// request ID.
+func logRequests(name string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := httpx.NewRecorder(w)
+		next.ServeHTTP(rec, r)
+		log.Printf("%s request_id=%s method=%s path=%s status=%d bytes=%d duration=%s",
+			name, requestid.FromContext(r.Context()), r.Method, r.URL.Path,
+			rec.Status, rec.Bytes, time.Since(start))
+	})
+}
+
+// healthz reports that the process is alive.
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, "ok")
+}
+
+// readyz reports whether the service should receive traffic. It turns
+// false as soon as shutdown begins.
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		problem.Error(w, r, http.StatusServiceUnavailable, problem.CodeUnavailable, "service is not ready")
+		return
+	}
+	writeStatus(w, "ready")
+}
+
+func writeStatus(w http.ResponseWriter, status string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": status})
+}
@@ -0,0 +1,143 @@
+// Package tracing records OpenTelemetry spans for the ENVR HTTP services,
+// exports them over OTLP/HTTP and propagates W3C trace context between
+// services.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/shellworlds/ENVR/pkg/config"
+	"github.com/shellworlds/ENVR/pkg/httpx"
+	"github.com/shellworlds/ENVR/pkg/requestid"
+)
+
+// scope names the instrumentation in exported spans.
+const scope = "github.com/shellworlds/ENVR/pkg/tracing"
+
+// propagator reads and writes the W3C traceparent, tracestate and baggage
+// headers.
+var propagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// Provider records a service's spans.
+type Provider struct {
+	tp     *sdktrace.TracerProvider // nil when export is off
+	tracer trace.Tracer
+}
+
+// New returns a Provider for the service name. When cfg has no OTLP
+// endpoint, spans are not recorded but incoming trace context still reaches
+// handlers and outgoing calls.
+func New(name string, cfg config.Tracing) (*Provider, error) {
+	if !cfg.Enabled() {
+		return &Provider{tracer: noop.NewTracerProvider().Tracer(scope)}, nil
+	}
+	exp, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.OTLPEndpoint, "/")+"/v1/traces"))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(name))),
+	)
+	return &Provider{tp: tp, tracer: tp.Tracer(scope)}, nil
+}
+
+// Shutdown exports any buffered spans and stops the Provider.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p.tp == nil {
+		return nil
+	}
+	return p.tp.Shutdown(ctx)
+}
+
+// Middleware starts a server span for each request to next, continuing the
+// trace in the request's traceparent header. Like the metrics route label,
+// the span name uses the path of the ServeMux pattern that mux would pick.
+// Responses with a 5xx status mark the span as failed.
+func (p *Provider) Middleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		name := r.Method
+		attrs := []attribute.KeyValue{
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLPath(r.URL.Path),
+		}
+		if mux != nil {
+			if _, pattern := mux.Handler(r); pattern != "" {
+				route := httpx.PatternPath(pattern)
+				name += " " + route
+				attrs = append(attrs, semconv.HTTPRoute(route))
+			}
+		}
+		if id := requestid.FromContext(ctx); id != "" {
+			attrs = append(attrs, attribute.String("envr.request_id", id))
+		}
+
+		ctx, span := p.tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		rec := httpx.NewRecorder(w)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+		span.SetAttributes(semconv.HTTPResponseStatusCode(rec.Status))
+		if rec.Status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(rec.Status))
+		}
+	})
+}
+
+// Transport is an http.RoundTripper that records a client span for each
+// outgoing request and sends the trace context in its traceparent and
+// baggage headers. The span is recorded by the provider of the span in the
+// request's context, so calls made while serving a request join its trace.
+// Wrap a requestid.Transport to forward both.
+type Transport struct {
+	// Base is the underlying transport; http.DefaultTransport if nil.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer(scope).Start(
+		r.Context(), r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.ServerAddress(r.URL.Hostname()),
+			semconv.URLPath(r.URL.Path),
+		))
+	defer span.End()
+
+	r = r.Clone(ctx)
+	propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+	resp, err := base.RoundTrip(r)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 500 {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -17,17 +17,9 @@ if ! command -v go &> /dev/null; then
     fi
 fi
 
-# Create Go module if not exists
-if [ ! -f src/go/go.mod ]; then
-    cd src/go
-    go mod init quantum-jv-platform
-    cd ../..
-fi
-
 # Build Go application
-cd src/go
 echo "Building Go quantum API..."
-go build -o ../../build/go/quantum_api quantum_api.go
+go build -o build/go/quantum_api ./cmd/quantum-api
 
 if [ $? -eq 0 ]; then
     echo "Build successful!"
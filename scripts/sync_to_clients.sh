@@ -16,7 +16,11 @@ SYNC_FILES=(
     "src/node/server.js"
     "src/node/package.json"
     "src/cpp/quantum_simulator.cpp"
-    "src/go/quantum_api.go"
+    "cmd/quantum-api/"
+    "internal/"
+    "pkg/"
+    "go.mod"
+    "go.sum"
     "src/java/QuantumService.java"
     "README.md"
     "Makefile"
@@ -41,7 +45,7 @@ for client_dir in "${CLIENT_DIRS[@]}"; do
     mkdir -p "${client_dir}/src/react"
     mkdir -p "${client_dir}/src/node"
     mkdir -p "${client_dir}/src/cpp"
-    mkdir -p "${client_dir}/src/go"
+    mkdir -p "${client_dir}/cmd"
     mkdir -p "${client_dir}/src/java"
     mkdir -p "${client_dir}/scripts"
     mkdir -p "${client_dir}/docs"
@@ -11,7 +11,7 @@ echo ""
 
 # Go verification
 echo "2. Go Implementation:"
-go run src/go/verifier.go
+go run ./cmd/theorem-verifier
 echo ""
 
 # C++ verification
@@ -27,9 +27,7 @@ cd ../..
 Test Go
 echo ""
 echo "4. Testing Go:"
-cd src/go
 go version 2>/dev/null && echo "Go available" || echo "Go not installed"
-cd ../..
 
 Test Rust
 echo ""
@@ -82,7 +82,7 @@ required_files=(
     "src/python/module_theorem.py"
     "src/java/ModuleTheorem.java"
     "src/cpp/module_theorem.cpp"
-    "src/go/module_theorem.go"
+    "cmd/module-theorem/main.go"
     "src/rust/module_theorem.rs"
     "src/js/module-theorem.ts"
     "Makefile"